@@ -10,23 +10,23 @@ mkdir -p ../../bin
 
 # Build for current platform
 echo "Building for current platform..."
-go build -o ../../bin/memory-cli main.go
+go build -o ../../bin/memory-cli .
 
 # Build for all platforms
 echo "Building for Linux x64..."
-GOOS=linux GOARCH=amd64 go build -o ../../bin/memory-cli-linux-x64 main.go
+GOOS=linux GOARCH=amd64 go build -o ../../bin/memory-cli-linux-x64 .
 
 echo "Building for macOS x64..."
-GOOS=darwin GOARCH=amd64 go build -o ../../bin/memory-cli-darwin-x64 main.go
+GOOS=darwin GOARCH=amd64 go build -o ../../bin/memory-cli-darwin-x64 .
 
 echo "Building for macOS ARM64..."
-GOOS=darwin GOARCH=arm64 go build -o ../../bin/memory-cli-darwin-arm64 main.go
+GOOS=darwin GOARCH=arm64 go build -o ../../bin/memory-cli-darwin-arm64 .
 
 echo "Building for Windows x64..."
-GOOS=windows GOARCH=amd64 go build -o ../../bin/memory-cli-windows-x64.exe main.go
+GOOS=windows GOARCH=amd64 go build -o ../../bin/memory-cli-windows-x64.exe .
 
 echo "Building for Windows ARM64..."
-GOOS=windows GOARCH=arm64 go build -o ../../bin/memory-cli-windows-arm64.exe main.go
+GOOS=windows GOARCH=arm64 go build -o ../../bin/memory-cli-windows-arm64.exe .
 
 echo "Build complete! Binaries available in bin/ directory:"
 ls -la ../../bin/memory-cli*
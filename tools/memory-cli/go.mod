@@ -0,0 +1,5 @@
+module github.com/Nehonix-Team/XyPriss/tools/memory-cli
+
+go 1.18
+
+require golang.org/x/sys v0.30.0
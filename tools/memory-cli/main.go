@@ -2,12 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // MemoryInfo represents system memory information
@@ -23,11 +28,92 @@ type MemoryInfo struct {
 	SwapTotal       uint64  `json:"swapTotal"`       // Total swap space
 	SwapUsed        uint64  `json:"swapUsed"`        // Used swap space
 	SwapFree        uint64  `json:"swapFree"`        // Free swap space
+
+	Container    *ContainerInfo  `json:"container,omitempty"`    // cgroup limits when running in a container
+	Pressure     *MemoryPressure `json:"pressure,omitempty"`     // PSI memory pressure (Linux 4.20+)
+	TopProcesses []ProcessMemory `json:"topProcesses,omitempty"` // Biggest memory consumers (--top)
+
+	Timestamp int64        `json:"timestamp,omitempty"` // Sample time in Unix milliseconds (watch mode)
+	Delta     *MemoryDelta `json:"delta,omitempty"`     // Change since the previous sample (watch mode)
+
+	swap *swapCounters // Cumulative swap counters, nil where the platform has none
+}
+
+// MemoryPressure represents Linux PSI memory pressure from /proc/pressure/memory
+type MemoryPressure struct {
+	Some PressureStats `json:"some"` // Share of time at least one task stalled on memory
+	Full PressureStats `json:"full"` // Share of time all non-idle tasks stalled on memory
+}
+
+// PressureStats represents one PSI line
+type PressureStats struct {
+	Avg10  float64 `json:"avg10"`  // Stall percentage over the last 10 seconds
+	Avg60  float64 `json:"avg60"`  // Stall percentage over the last 60 seconds
+	Avg300 float64 `json:"avg300"` // Stall percentage over the last 300 seconds
+	Total  uint64  `json:"total"`  // Total stall time in microseconds
+}
+
+// ProcessMemory represents the memory footprint of a single process
+type ProcessMemory struct {
+	PID          int    `json:"pid"`
+	Name         string `json:"name"`
+	RSS          uint64 `json:"rss"`                    // Resident set size (working set on Windows) in bytes
+	USS          uint64 `json:"uss,omitempty"`          // Unique set size in bytes (Linux, when readable)
+	PrivateBytes uint64 `json:"privateBytes,omitempty"` // Private committed bytes (Windows)
+}
+
+// MemoryDelta represents the change between two consecutive watch samples
+type MemoryDelta struct {
+	IntervalSeconds float64 `json:"intervalSeconds"`   // Elapsed time since the previous sample
+	UsedMemoryDelta int64   `json:"usedMemoryDelta"`   // Change in used memory in bytes
+	GrowthRate      float64 `json:"growthRate"`        // Used memory growth in bytes per second
+	SwapIn          *uint64 `json:"swapIn,omitempty"`  // Bytes swapped in during the interval (Linux, macOS)
+	SwapOut         *uint64 `json:"swapOut,omitempty"` // Bytes swapped out during the interval (Linux, macOS)
+}
+
+// swapCounters holds cumulative swap-in/swap-out bytes since boot
+type swapCounters struct {
+	In  uint64
+	Out uint64
+}
+
+// ContainerInfo represents the effective resources of the cgroup the process runs in
+type ContainerInfo struct {
+	CgroupVersion   int     `json:"cgroupVersion"`   // 1 or 2
+	MemoryLimit     uint64  `json:"memoryLimit"`     // Effective memory limit in bytes (host total if unlimited)
+	MemoryUsage     uint64  `json:"memoryUsage"`     // Memory charged to the cgroup, excluding inactive file cache
+	AvailableMemory uint64  `json:"availableMemory"` // Memory still available under the limit
+	UsagePercentage float64 `json:"usagePercentage"` // Usage percentage relative to the limit
+	CPULimit        float64 `json:"cpuLimit"`        // CPU quota in cores (0 if unlimited)
 }
 
 func main() {
-	if len(os.Args) > 1 && os.Args[1] == "--help" {
-		printHelp()
+	watch := flag.Bool("watch", false, "")
+	interval := flag.Duration("interval", time.Second, "")
+	pretty := flag.Bool("pretty", false, "")
+	top := flag.Int("top", 0, "")
+	help := flag.Bool("help", false, "")
+	flag.BoolVar(help, "h", false, "")
+	// Parse errors must not print on stdout, which carries the JSON output
+	flag.Usage = func() { printHelp(os.Stderr) }
+	flag.Parse()
+
+	if *help {
+		printHelp(os.Stdout)
+		return
+	}
+
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+		os.Exit(1)
+	}
+	if *top < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --top must not be negative\n")
+		os.Exit(1)
+	}
+
+	if *watch {
+		watchMemoryInfo(*interval, *pretty, *top)
 		return
 	}
 
@@ -36,29 +122,168 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error getting memory info: %v\n", err)
 		os.Exit(1)
 	}
+	addTopProcesses(memInfo, *top)
 
-	output, err := json.Marshal(memInfo)
-	if err != nil {
+	if err := printMemoryInfo(memInfo, *pretty); err != nil {
 		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
 		os.Exit(1)
-	} 
+	}
+}
+
+// printHelp writes the usage text to w
+func printHelp(w io.Writer) {
+	fmt.Fprintln(w, "XyPriss Memory Info CLI")
+	fmt.Fprintln(w, "Usage: memory-cli [--help] [--watch] [--interval 1s] [--pretty] [--top N]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Options:")
+	fmt.Fprintln(w, "  --watch          Emit one snapshot per interval until interrupted")
+	fmt.Fprintln(w, "  --interval <d>   Sampling interval in watch mode (default 1s)")
+	fmt.Fprintln(w, "  --pretty         Human-readable output instead of JSON")
+	fmt.Fprintln(w, "  --top <n>        Include the n biggest memory consumers")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Returns system memory information in JSON format:")
+	fmt.Fprintln(w, "- totalMemory: Total system memory in bytes")
+	fmt.Fprintln(w, "- availableMemory: Memory available for applications")
+	fmt.Fprintln(w, "- freeMemory: Truly free memory")
+	fmt.Fprintln(w, "- usedMemory: Currently used memory")
+	fmt.Fprintln(w, "- usagePercentage: Memory usage percentage")
+	fmt.Fprintln(w, "- buffersMemory: Buffer memory (Linux/Unix)")
+	fmt.Fprintln(w, "- cachedMemory: Cached memory (Linux/Unix)")
+	fmt.Fprintln(w, "- swapTotal/swapUsed/swapFree: Swap space information")
+	fmt.Fprintln(w, "- container: cgroup memory/CPU limits when running in a container (Linux)")
+	fmt.Fprintln(w, "- pressure: PSI memory pressure some/full averages (Linux 4.20+)")
+	fmt.Fprintln(w, "- topProcesses: pid, name, rss, uss (Linux) and privateBytes (Windows) of the biggest consumers (--top)")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "In watch mode each line also carries a timestamp and, from the second")
+	fmt.Fprintln(w, "tick on, a delta object (usedMemoryDelta, growthRate in bytes/s,")
+	fmt.Fprintln(w, "swapIn/swapOut in bytes during the interval on Linux and macOS).")
+}
+
+// watchMemoryInfo emits a snapshot per tick, with deltas against the previous tick
+func watchMemoryInfo(interval time.Duration, pretty bool, top int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev *MemoryInfo
+	var prevAt time.Time
+
+	for {
+		now := time.Now()
+		memInfo, err := getMemoryInfo()
+		if err != nil {
+			// Keep the stream alive; a single failed sample is not fatal
+			fmt.Fprintf(os.Stderr, "Error getting memory info: %v\n", err)
+		} else {
+			memInfo.Timestamp = now.UnixMilli()
+			addTopProcesses(memInfo, top)
+
+			if prev != nil {
+				memInfo.Delta = newMemoryDelta(prev, memInfo, now.Sub(prevAt).Seconds())
+			}
+
+			if err := printMemoryInfo(memInfo, pretty); err != nil {
+				fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			}
+
+			prev, prevAt = memInfo, now
+		}
+
+		<-ticker.C
+	}
+}
+
+// newMemoryDelta computes the change between two samples taken elapsed seconds apart
+func newMemoryDelta(prev, cur *MemoryInfo, elapsed float64) *MemoryDelta {
+	delta := &MemoryDelta{
+		IntervalSeconds: elapsed,
+		UsedMemoryDelta: int64(cur.UsedMemory) - int64(prev.UsedMemory),
+	}
+	if elapsed > 0 {
+		delta.GrowthRate = float64(delta.UsedMemoryDelta) / elapsed
+	}
+
+	// Only diff counters when both samples have them, otherwise a missed
+	// read would report every swap since boot as this interval's delta
+	if cur.swap != nil && prev.swap != nil {
+		swapIn := cur.swap.In - prev.swap.In
+		swapOut := cur.swap.Out - prev.swap.Out
+		delta.SwapIn, delta.SwapOut = &swapIn, &swapOut
+	}
+
+	return delta
+}
+
+// printMemoryInfo writes a snapshot to stdout as a JSON line or in human format
+func printMemoryInfo(memInfo *MemoryInfo, pretty bool) error {
+	if !pretty {
+		output, err := json.Marshal(memInfo)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	line := fmt.Sprintf("Memory: %.1f MB / %.1f MB used (%.2f%%), %.1f MB available | Swap: %.1f MB / %.1f MB",
+		bytesToMB(memInfo.UsedMemory), bytesToMB(memInfo.TotalMemory), memInfo.UsagePercentage,
+		bytesToMB(memInfo.AvailableMemory), bytesToMB(memInfo.SwapUsed), bytesToMB(memInfo.SwapTotal))
+
+	if c := memInfo.Container; c != nil {
+		line += fmt.Sprintf(" | Container: %.1f MB / %.1f MB (%.2f%%)",
+			bytesToMB(c.MemoryUsage), bytesToMB(c.MemoryLimit), c.UsagePercentage)
+	}
+
+	if d := memInfo.Delta; d != nil {
+		line += fmt.Sprintf(" | Delta: %+.1f MB (%+.1f MB/s)",
+			float64(d.UsedMemoryDelta)/1024/1024, d.GrowthRate/1024/1024)
+		if d.SwapIn != nil && d.SwapOut != nil {
+			line += fmt.Sprintf(", swap in/out %.1f/%.1f MB", bytesToMB(*d.SwapIn), bytesToMB(*d.SwapOut))
+		}
+	}
+
+	if p := memInfo.Pressure; p != nil {
+		line += fmt.Sprintf(" | PSI some/full avg10: %.2f/%.2f", p.Some.Avg10, p.Full.Avg10)
+	}
+
+	for _, proc := range memInfo.TopProcesses {
+		line += fmt.Sprintf("\n  %8d  %-24s %10.1f MB", proc.PID, proc.Name, bytesToMB(proc.RSS))
+	}
+
+	if memInfo.Timestamp != 0 {
+		line = time.UnixMilli(memInfo.Timestamp).Format("15:04:05") + " " + line
+	}
 
-	fmt.Println(string(output))
+	fmt.Println(line)
+	return nil
 }
 
-func printHelp() {
-	fmt.Println("XyPriss Memory Info CLI")
-	fmt.Println("Usage: memory-cli [--help]")
-	fmt.Println("")
-	fmt.Println("Returns system memory information in JSON format:")
-	fmt.Println("- totalMemory: Total system memory in bytes")
-	fmt.Println("- availableMemory: Memory available for applications")
-	fmt.Println("- freeMemory: Truly free memory")
-	fmt.Println("- usedMemory: Currently used memory")
-	fmt.Println("- usagePercentage: Memory usage percentage")
-	fmt.Println("- buffersMemory: Buffer memory (Linux/Unix)")
-	fmt.Println("- cachedMemory: Cached memory (Linux/Unix)")
-	fmt.Println("- swapTotal/swapUsed/swapFree: Swap space information")
+// getLinuxSwapCounters reads cumulative swap-in/swap-out bytes from /proc/vmstat
+func getLinuxSwapCounters() *swapCounters {
+	data, err := os.ReadFile("/proc/vmstat")
+	if err != nil {
+		return nil
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	counters := &swapCounters{}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch parts[0] {
+		case "pswpin":
+			counters.In = value * pageSize
+		case "pswpout":
+			counters.Out = value * pageSize
+		}
+	}
+
+	return counters
 }
 
 func getMemoryInfo() (*MemoryInfo, error) {
@@ -180,9 +405,258 @@ func getLinuxMemoryInfo() (*MemoryInfo, error) {
 		memInfo.AvailableMemory = memInfo.FreeMemory + memInfo.BuffersMemory + memInfo.CachedMemory
 	}
 
+	memInfo.Container = getCgroupInfo("/proc/self/cgroup", "/sys/fs/cgroup", memInfo.TotalMemory)
+	memInfo.Pressure = getMemoryPressure()
+	memInfo.swap = getLinuxSwapCounters()
+
 	return memInfo, nil
 }
 
+// getMemoryPressure reads PSI memory pressure; it returns nil when PSI is unavailable
+func getMemoryPressure() *MemoryPressure {
+	data, err := os.ReadFile("/proc/pressure/memory")
+	if err != nil {
+		return nil
+	}
+
+	// Example: "some avg10=0.00 avg60=0.00 avg300=0.00 total=0"
+	pressure := &MemoryPressure{}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+
+		var stats *PressureStats
+		switch parts[0] {
+		case "some":
+			stats = &pressure.Some
+		case "full":
+			stats = &pressure.Full
+		default:
+			continue
+		}
+
+		for _, field := range parts[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "avg10":
+				stats.Avg10, _ = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stats.Avg60, _ = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stats.Avg300, _ = strconv.ParseFloat(value, 64)
+			case "total":
+				stats.Total, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+	}
+
+	return pressure
+}
+
+// getCgroupInfo detects cgroup v1/v2 limits for the current process, given the
+// path of its /proc/<pid>/cgroup file and the cgroup mount point.
+// It returns nil when no memory or CPU limit is in effect.
+func getCgroupInfo(procCgroupPath, cgroupRoot string, hostTotal uint64) *ContainerInfo {
+	data, err := os.ReadFile(procCgroupPath)
+	if err != nil {
+		return nil
+	}
+
+	var info *ContainerInfo
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		info = getCgroupV2Info(string(data), cgroupRoot)
+	} else {
+		info = getCgroupV1Info(string(data), cgroupRoot)
+	}
+	if info == nil {
+		return nil
+	}
+
+	// Limits at or above the host total are effectively unlimited
+	// (cgroup v1 reports "no limit" as a huge page-aligned value)
+	if info.MemoryLimit == 0 || info.MemoryLimit >= hostTotal {
+		if info.CPULimit == 0 {
+			return nil
+		}
+		info.MemoryLimit = hostTotal
+	}
+
+	if info.MemoryUsage < info.MemoryLimit {
+		info.AvailableMemory = info.MemoryLimit - info.MemoryUsage
+	}
+	info.UsagePercentage = calculateUsagePercentage(info.MemoryUsage, info.MemoryLimit)
+
+	return info
+}
+
+// getCgroupV2Info reads limits from the unified cgroup v2 hierarchy
+func getCgroupV2Info(procCgroup, root string) *ContainerInfo {
+	leaf := root
+	for _, line := range strings.Split(procCgroup, "\n") {
+		if strings.HasPrefix(line, "0::") {
+			candidate := filepath.Join(root, strings.TrimPrefix(line, "0::"))
+			// Inside a cgroup namespace the recorded path may not be mounted
+			if _, err := os.Stat(candidate); err == nil && strings.HasPrefix(candidate, root) {
+				leaf = candidate
+			}
+			break
+		}
+	}
+
+	info := &ContainerInfo{CgroupVersion: 2}
+
+	// Limits set on ancestors (e.g. MemoryMax= on a systemd slice) apply too,
+	// so walk up to the mounted root and keep the tightest ones
+	usageDir := leaf
+	for dir := leaf; ; dir = filepath.Dir(dir) {
+		if limit, err := readCgroupValue(filepath.Join(dir, "memory.max")); err == nil && limit > 0 &&
+			(info.MemoryLimit == 0 || limit < info.MemoryLimit) {
+			info.MemoryLimit = limit
+			usageDir = dir
+		}
+		if cpu := readCgroupV2CPULimit(dir); cpu > 0 && (info.CPULimit == 0 || cpu < info.CPULimit) {
+			info.CPULimit = cpu
+		}
+		if dir == root {
+			break
+		}
+	}
+
+	// Usage is charged against the cgroup whose limit binds, siblings included
+	current, _ := readCgroupValue(filepath.Join(usageDir, "memory.current"))
+	inactive := readCgroupStat(filepath.Join(usageDir, "memory.stat"), "inactive_file")
+	if current > inactive {
+		info.MemoryUsage = current - inactive
+	}
+
+	return info
+}
+
+// readCgroupV2CPULimit reads cpu.max ("<quota|max> <period>") as a number of cores (0 if unlimited)
+func readCgroupV2CPULimit(dir string) float64 {
+	data, err := os.ReadFile(filepath.Join(dir, "cpu.max"))
+	if err != nil {
+		return 0
+	}
+	parts := strings.Fields(string(data))
+	if len(parts) != 2 || parts[0] == "max" {
+		return 0
+	}
+	quota, qErr := strconv.ParseFloat(parts[0], 64)
+	period, pErr := strconv.ParseFloat(parts[1], 64)
+	if qErr != nil || pErr != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// getCgroupV1Info reads limits from the memory and cpu controllers of a cgroup v1 hierarchy
+func getCgroupV1Info(procCgroup, cgroupRoot string) *ContainerInfo {
+	paths := map[string]string{}
+	for _, line := range strings.Split(procCgroup, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			paths[controller] = parts[2]
+		}
+	}
+
+	memPath, ok := paths["memory"]
+	if !ok {
+		return nil
+	}
+	memDir := resolveCgroupV1Dir(cgroupRoot, "memory", memPath)
+
+	info := &ContainerInfo{CgroupVersion: 1}
+	info.MemoryLimit, _ = readCgroupValue(filepath.Join(memDir, "memory.limit_in_bytes"))
+	// hierarchical_memory_limit accounts for limits set on ancestor cgroups
+	hierarchical := readCgroupStat(filepath.Join(memDir, "memory.stat"), "hierarchical_memory_limit")
+	if hierarchical > 0 && (info.MemoryLimit == 0 || hierarchical < info.MemoryLimit) {
+		info.MemoryLimit = hierarchical
+	}
+	usage, _ := readCgroupValue(filepath.Join(memDir, "memory.usage_in_bytes"))
+	inactive := readCgroupStat(filepath.Join(memDir, "memory.stat"), "total_inactive_file")
+	if usage > inactive {
+		info.MemoryUsage = usage - inactive
+	}
+
+	if cpuPath, ok := paths["cpu"]; ok {
+		// CFS quotas are not hierarchical in the files, so check every ancestor
+		root := filepath.Join(cgroupRoot, "cpu")
+		for dir := resolveCgroupV1Dir(cgroupRoot, "cpu", cpuPath); ; dir = filepath.Dir(dir) {
+			quota, qErr := readCgroupInt(filepath.Join(dir, "cpu.cfs_quota_us"))
+			period, pErr := readCgroupInt(filepath.Join(dir, "cpu.cfs_period_us"))
+			if qErr == nil && pErr == nil && quota > 0 && period > 0 {
+				if cpu := float64(quota) / float64(period); info.CPULimit == 0 || cpu < info.CPULimit {
+					info.CPULimit = cpu
+				}
+			}
+			if dir == root {
+				break
+			}
+		}
+	}
+
+	return info
+}
+
+// resolveCgroupV1Dir returns the controller directory for a cgroup path,
+// falling back to the controller root when the path is not visible (cgroup namespaces)
+func resolveCgroupV1Dir(cgroupRoot, controller, path string) string {
+	root := filepath.Join(cgroupRoot, controller)
+	dir := filepath.Join(root, path)
+	if _, err := os.Stat(dir); err != nil || !strings.HasPrefix(dir, root) {
+		return root
+	}
+	return dir
+}
+
+// readCgroupValue reads a single unsigned value from a cgroup file ("max" is reported as 0)
+func readCgroupValue(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// readCgroupInt reads a single signed value from a cgroup file (v1 uses -1 for unlimited)
+func readCgroupInt(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readCgroupStat extracts a named counter from a memory.stat file
+func readCgroupStat(path, key string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 2 && parts[0] == key {
+			if value, err := strconv.ParseUint(parts[1], 10, 64); err == nil {
+				return value
+			}
+		}
+	}
+	return 0
+}
+
 // getDarwinMemoryInfo gets memory info on macOS using vm_stat and sysctl
 func getDarwinMemoryInfo() (*MemoryInfo, error) {
 	memInfo := &MemoryInfo{Platform: "darwin"}
@@ -211,6 +685,8 @@ func getDarwinMemoryInfo() (*MemoryInfo, error) {
 	lines := strings.Split(string(vmStatOutput), "\n")
 	var pageSize uint64 = 4096 // Default page size
 	var freePages, inactivePages, speculativePages uint64
+	var swapIns, swapOuts uint64
+	hasSwapCounters := false
 
 	for _, line := range lines {
 		if strings.Contains(line, "page size of") {
@@ -230,12 +706,20 @@ func getDarwinMemoryInfo() (*MemoryInfo, error) {
 			inactivePages = parseVmStatValue(line)
 		} else if strings.Contains(line, "Pages speculative:") {
 			speculativePages = parseVmStatValue(line)
+		} else if strings.HasPrefix(line, "Swapins:") {
+			swapIns = parseVmStatValue(line)
+			hasSwapCounters = true
+		} else if strings.HasPrefix(line, "Swapouts:") {
+			swapOuts = parseVmStatValue(line)
 		}
 	}
 
 	// Calculate memory values
 	memInfo.FreeMemory = freePages * pageSize
 	memInfo.AvailableMemory = (freePages + inactivePages + speculativePages) * pageSize
+	if hasSwapCounters {
+		memInfo.swap = &swapCounters{In: swapIns * pageSize, Out: swapOuts * pageSize}
+	}
 	memInfo.UsedMemory = memInfo.TotalMemory - memInfo.AvailableMemory
 	memInfo.UsagePercentage = calculateUsagePercentage(memInfo.UsedMemory, memInfo.TotalMemory)
 
@@ -309,93 +793,137 @@ func parseMemoryValue(value string) uint64 {
 	}
 }
 
-// getWindowsMemoryInfo gets memory info on Windows using wmic
-func getWindowsMemoryInfo() (*MemoryInfo, error) {
-	memInfo := &MemoryInfo{Platform: "windows"}
+// addTopProcesses attaches the n biggest memory consumers to memInfo.
+// Failures are reported on stderr without discarding the system snapshot.
+func addTopProcesses(memInfo *MemoryInfo, n int) {
+	if n <= 0 {
+		return
+	}
 
-	// Get total physical memory
-	totalCmd := exec.Command("wmic", "computersystem", "get", "TotalPhysicalMemory", "/value")
-	totalOutput, err := totalCmd.Output()
+	procs, err := getTopProcesses(n)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get total memory: %v", err)
+		fmt.Fprintf(os.Stderr, "Error getting process list: %v\n", err)
+		return
 	}
+	memInfo.TopProcesses = procs
+}
 
-	for _, line := range strings.Split(string(totalOutput), "\n") {
-		if strings.Contains(line, "TotalPhysicalMemory=") {
-			valueStr := strings.TrimSpace(strings.Split(line, "=")[1])
-			if total, err := strconv.ParseUint(valueStr, 10, 64); err == nil {
-				memInfo.TotalMemory = total
-			}
-			break
+// getTopProcesses returns the n processes with the largest resident set, sorted descending
+func getTopProcesses(n int) ([]ProcessMemory, error) {
+	var procs []ProcessMemory
+	var err error
+
+	switch runtime.GOOS {
+	case "linux":
+		procs, err = getLinuxProcesses()
+	case "windows":
+		procs, err = getWindowsProcesses()
+	default:
+		procs, err = getPsProcesses()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].RSS > procs[j].RSS })
+	if len(procs) > n {
+		procs = procs[:n]
+	}
+
+	// USS requires walking smaps, so only compute it for the selected processes
+	if runtime.GOOS == "linux" {
+		for i := range procs {
+			procs[i].USS = getLinuxProcessUSS(procs[i].PID)
 		}
 	}
 
-	// Get available memory
-	availCmd := exec.Command("wmic", "OS", "get", "FreePhysicalMemory", "/value")
-	availOutput, err := availCmd.Output()
+	return procs, nil
+}
+
+// getLinuxProcesses lists processes and their RSS from /proc
+func getLinuxProcesses() ([]ProcessMemory, error) {
+	entries, err := os.ReadDir("/proc")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get available memory: %v", err)
+		return nil, fmt.Errorf("failed to read /proc: %v", err)
 	}
 
-	for _, line := range strings.Split(string(availOutput), "\n") {
-		if strings.Contains(line, "FreePhysicalMemory=") {
-			valueStr := strings.TrimSpace(strings.Split(line, "=")[1])
-			if free, err := strconv.ParseUint(valueStr, 10, 64); err == nil {
-				memInfo.FreeMemory = free * 1024 // Convert from KB to bytes
-				memInfo.AvailableMemory = memInfo.FreeMemory
-			}
-			break
+	pageSize := uint64(os.Getpagesize())
+	var procs []ProcessMemory
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
 		}
-	}
 
-	// Get more accurate available memory using Performance Counters
-	perfCmd := exec.Command("typeperf", "\\Memory\\Available Bytes", "-sc", "1")
-	if perfOutput, err := perfCmd.Output(); err == nil {
-		lines := strings.Split(string(perfOutput), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "Available Bytes") {
-				parts := strings.Split(line, ",")
-				if len(parts) >= 2 {
-					valueStr := strings.Trim(strings.TrimSpace(parts[1]), "\"")
-					if avail, err := strconv.ParseFloat(valueStr, 64); err == nil {
-						memInfo.AvailableMemory = uint64(avail)
-					}
-				}
-				break
-			}
+		// Processes may exit while we iterate; skip them silently
+		statm, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "statm"))
+		if err != nil {
+			continue
 		}
+		fields := strings.Fields(string(statm))
+		if len(fields) < 2 {
+			continue
+		}
+		resident, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil || resident == 0 {
+			continue // Kernel threads have no resident memory
+		}
+
+		name := ""
+		if comm, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "comm")); err == nil {
+			name = strings.TrimSpace(string(comm))
+		}
+
+		procs = append(procs, ProcessMemory{PID: pid, Name: name, RSS: resident * pageSize})
 	}
 
-	// Calculate derived values
-	memInfo.UsedMemory = memInfo.TotalMemory - memInfo.AvailableMemory
-	memInfo.UsagePercentage = calculateUsagePercentage(memInfo.UsedMemory, memInfo.TotalMemory)
+	return procs, nil
+}
 
-	// Get swap/page file info
-	swapCmd := exec.Command("wmic", "pagefile", "get", "Size,Usage", "/format:csv")
-	if swapOutput, err := swapCmd.Output(); err == nil {
-		parseWindowsSwap(string(swapOutput), memInfo)
+// getLinuxProcessUSS sums private pages from smaps_rollup (0 if not permitted)
+func getLinuxProcessUSS(pid int) uint64 {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "smaps_rollup"))
+	if err != nil {
+		return 0
 	}
 
-	return memInfo, nil
+	var uss uint64
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "Private_Clean:") || strings.HasPrefix(line, "Private_Dirty:") {
+			if _, value, err := parseMemInfoLine(line); err == nil {
+				uss += value
+			}
+		}
+	}
+	return uss
 }
 
-// parseWindowsSwap parses Windows page file information
-func parseWindowsSwap(output string, memInfo *MemoryInfo) {
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		if line == "" || strings.Contains(line, "Node,Size,Usage") {
+// getPsProcesses lists processes and their RSS using ps (macOS and other Unix systems)
+func getPsProcesses() ([]ProcessMemory, error) {
+	output, err := exec.Command("ps", "-axo", "pid=,rss=,comm=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run ps: %v", err)
+	}
+
+	var procs []ProcessMemory
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
 			continue
 		}
-
-		parts := strings.Split(line, ",")
-		if len(parts) >= 3 {
-			if size, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64); err == nil {
-				memInfo.SwapTotal += size * 1024 * 1024 // Convert MB to bytes
-			}
-			if usage, err := strconv.ParseUint(strings.TrimSpace(parts[2]), 10, 64); err == nil {
-				memInfo.SwapUsed += usage * 1024 * 1024 // Convert MB to bytes
-			}
+		pid, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
 		}
+		rss, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		// comm may contain spaces (full executable path on macOS)
+		name := filepath.Base(strings.Join(parts[2:], " "))
+		procs = append(procs, ProcessMemory{PID: pid, Name: name, RSS: rss * 1024}) // ps reports KB
 	}
-	memInfo.SwapFree = memInfo.SwapTotal - memInfo.SwapUsed
+
+	return procs, nil
 }
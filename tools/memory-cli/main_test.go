@@ -0,0 +1,236 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	mib       = 1024 * 1024
+	hostTotal = 8 * 1024 * mib
+)
+
+// writeFixture creates a file (and its parent directories) under root
+func writeFixture(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newCgroupFixture returns a /proc/self/cgroup path and an empty cgroup root
+func newCgroupFixture(t *testing.T, procCgroup string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writeFixture(t, dir, "proc/self/cgroup", procCgroup)
+	root := filepath.Join(dir, "cgroup")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "proc/self/cgroup"), root
+}
+
+func TestCgroupV2AncestorLimits(t *testing.T) {
+	proc, root := newCgroupFixture(t, "0::/app.slice/worker.scope\n")
+	writeFixture(t, root, "cgroup.controllers", "cpu memory")
+	writeFixture(t, root, "app.slice/worker.scope/memory.max", "max\n")
+	writeFixture(t, root, "app.slice/worker.scope/memory.current", "1000\n")
+	writeFixture(t, root, "app.slice/worker.scope/cpu.max", "max 100000\n")
+	writeFixture(t, root, "app.slice/memory.max", "1073741824\n")
+	writeFixture(t, root, "app.slice/memory.current", "536870912\n")
+	writeFixture(t, root, "app.slice/memory.stat", "anon 1\ninactive_file 67108864\n")
+	writeFixture(t, root, "app.slice/cpu.max", "150000 100000\n")
+	// The root carries a looser memory limit but a tighter CPU quota
+	writeFixture(t, root, "memory.max", "2147483648\n")
+	writeFixture(t, root, "cpu.max", "50000 100000\n")
+
+	info := getCgroupInfo(proc, root, hostTotal)
+	if info == nil {
+		t.Fatal("expected container info, got nil")
+	}
+	if info.CgroupVersion != 2 {
+		t.Errorf("CgroupVersion = %d, want 2", info.CgroupVersion)
+	}
+	if info.MemoryLimit != 1024*mib {
+		t.Errorf("MemoryLimit = %d, want %d", info.MemoryLimit, 1024*mib)
+	}
+	// Usage comes from app.slice, whose limit binds, minus inactive file cache
+	if info.MemoryUsage != 448*mib {
+		t.Errorf("MemoryUsage = %d, want %d", info.MemoryUsage, 448*mib)
+	}
+	if info.AvailableMemory != 576*mib {
+		t.Errorf("AvailableMemory = %d, want %d", info.AvailableMemory, 576*mib)
+	}
+	if info.CPULimit != 0.5 {
+		t.Errorf("CPULimit = %v, want 0.5", info.CPULimit)
+	}
+}
+
+func TestCgroupV2NamespaceFallback(t *testing.T) {
+	// Inside a cgroup namespace the recorded path is not mounted; the root is the container
+	proc, root := newCgroupFixture(t, "0::/kubepods/pod1/ctr\n")
+	writeFixture(t, root, "cgroup.controllers", "cpu memory")
+	writeFixture(t, root, "memory.max", "268435456\n")
+	writeFixture(t, root, "memory.current", "134217728\n")
+	writeFixture(t, root, "cpu.max", "max 100000\n")
+
+	info := getCgroupInfo(proc, root, hostTotal)
+	if info == nil {
+		t.Fatal("expected container info, got nil")
+	}
+	if info.MemoryLimit != 256*mib || info.MemoryUsage != 128*mib {
+		t.Errorf("MemoryLimit/MemoryUsage = %d/%d, want %d/%d", info.MemoryLimit, info.MemoryUsage, 256*mib, 128*mib)
+	}
+	if info.CPULimit != 0 {
+		t.Errorf("CPULimit = %v, want 0", info.CPULimit)
+	}
+}
+
+func TestCgroupV2Unlimited(t *testing.T) {
+	proc, root := newCgroupFixture(t, "0::/user.slice\n")
+	writeFixture(t, root, "cgroup.controllers", "cpu memory")
+	writeFixture(t, root, "user.slice/memory.max", "max\n")
+	writeFixture(t, root, "user.slice/memory.current", "1048576\n")
+	writeFixture(t, root, "user.slice/cpu.max", "max 100000\n")
+
+	if info := getCgroupInfo(proc, root, hostTotal); info != nil {
+		t.Errorf("expected nil for unlimited cgroup, got %+v", info)
+	}
+}
+
+func TestCgroupLimitAboveHostTotal(t *testing.T) {
+	t.Run("memory only", func(t *testing.T) {
+		proc, root := newCgroupFixture(t, "0::/\n")
+		writeFixture(t, root, "cgroup.controllers", "cpu memory")
+		writeFixture(t, root, "memory.max", "17179869184\n")
+
+		if info := getCgroupInfo(proc, root, hostTotal); info != nil {
+			t.Errorf("expected nil when the limit exceeds the host total, got %+v", info)
+		}
+	})
+
+	t.Run("with cpu quota", func(t *testing.T) {
+		proc, root := newCgroupFixture(t, "0::/\n")
+		writeFixture(t, root, "cgroup.controllers", "cpu memory")
+		writeFixture(t, root, "memory.max", "17179869184\n")
+		writeFixture(t, root, "memory.current", "1073741824\n")
+		writeFixture(t, root, "cpu.max", "200000 100000\n")
+
+		info := getCgroupInfo(proc, root, hostTotal)
+		if info == nil {
+			t.Fatal("expected container info, got nil")
+		}
+		if info.MemoryLimit != hostTotal {
+			t.Errorf("MemoryLimit = %d, want host total %d", info.MemoryLimit, uint64(hostTotal))
+		}
+		if info.CPULimit != 2 {
+			t.Errorf("CPULimit = %v, want 2", info.CPULimit)
+		}
+	})
+}
+
+func TestCgroupV1HierarchicalLimits(t *testing.T) {
+	proc, root := newCgroupFixture(t, "4:memory:/docker/abc\n2:cpu,cpuacct:/docker/abc\n")
+	writeFixture(t, root, "memory/docker/abc/memory.limit_in_bytes", "9223372036854771712\n")
+	writeFixture(t, root, "memory/docker/abc/memory.usage_in_bytes", "104857600\n")
+	writeFixture(t, root, "memory/docker/abc/memory.stat",
+		"hierarchical_memory_limit 536870912\ntotal_inactive_file 1048576\n")
+	writeFixture(t, root, "cpu/docker/abc/cpu.cfs_quota_us", "-1\n")
+	writeFixture(t, root, "cpu/docker/abc/cpu.cfs_period_us", "100000\n")
+	writeFixture(t, root, "cpu/docker/cpu.cfs_quota_us", "200000\n")
+	writeFixture(t, root, "cpu/docker/cpu.cfs_period_us", "100000\n")
+	writeFixture(t, root, "cpu/cpu.cfs_quota_us", "-1\n")
+	writeFixture(t, root, "cpu/cpu.cfs_period_us", "100000\n")
+
+	info := getCgroupInfo(proc, root, hostTotal)
+	if info == nil {
+		t.Fatal("expected container info, got nil")
+	}
+	if info.CgroupVersion != 1 {
+		t.Errorf("CgroupVersion = %d, want 1", info.CgroupVersion)
+	}
+	if info.MemoryLimit != 512*mib {
+		t.Errorf("MemoryLimit = %d, want %d", info.MemoryLimit, 512*mib)
+	}
+	if info.MemoryUsage != 99*mib {
+		t.Errorf("MemoryUsage = %d, want %d", info.MemoryUsage, 99*mib)
+	}
+	if info.CPULimit != 2 {
+		t.Errorf("CPULimit = %v, want 2", info.CPULimit)
+	}
+}
+
+func TestCgroupV1NamespaceFallback(t *testing.T) {
+	proc, root := newCgroupFixture(t, "4:memory:/docker/hidden\n")
+	writeFixture(t, root, "memory/memory.limit_in_bytes", "268435456\n")
+	writeFixture(t, root, "memory/memory.usage_in_bytes", "67108864\n")
+
+	info := getCgroupInfo(proc, root, hostTotal)
+	if info == nil {
+		t.Fatal("expected container info, got nil")
+	}
+	if info.MemoryLimit != 256*mib || info.MemoryUsage != 64*mib {
+		t.Errorf("MemoryLimit/MemoryUsage = %d/%d, want %d/%d", info.MemoryLimit, info.MemoryUsage, 256*mib, 64*mib)
+	}
+}
+
+func TestCgroupV1Unlimited(t *testing.T) {
+	proc, root := newCgroupFixture(t, "4:memory:/\n2:cpu,cpuacct:/\n")
+	writeFixture(t, root, "memory/memory.limit_in_bytes", "9223372036854771712\n")
+	writeFixture(t, root, "memory/memory.usage_in_bytes", "104857600\n")
+	writeFixture(t, root, "memory/memory.stat", "hierarchical_memory_limit 9223372036854771712\n")
+	writeFixture(t, root, "cpu/cpu.cfs_quota_us", "-1\n")
+	writeFixture(t, root, "cpu/cpu.cfs_period_us", "100000\n")
+
+	if info := getCgroupInfo(proc, root, hostTotal); info != nil {
+		t.Errorf("expected nil for unlimited cgroup, got %+v", info)
+	}
+}
+
+func TestCgroupMissingProcFile(t *testing.T) {
+	dir := t.TempDir()
+	if info := getCgroupInfo(filepath.Join(dir, "missing"), dir, hostTotal); info != nil {
+		t.Errorf("expected nil without /proc/self/cgroup, got %+v", info)
+	}
+}
+
+func TestNewMemoryDelta(t *testing.T) {
+	prev := &MemoryInfo{UsedMemory: 100 * mib, swap: &swapCounters{In: 10 * mib, Out: 20 * mib}}
+	cur := &MemoryInfo{UsedMemory: 120 * mib, swap: &swapCounters{In: 12 * mib, Out: 20 * mib}}
+
+	delta := newMemoryDelta(prev, cur, 2)
+	if delta.UsedMemoryDelta != 20*mib {
+		t.Errorf("UsedMemoryDelta = %d, want %d", delta.UsedMemoryDelta, 20*mib)
+	}
+	if delta.GrowthRate != 10*mib {
+		t.Errorf("GrowthRate = %v, want %d", delta.GrowthRate, 10*mib)
+	}
+	if delta.SwapIn == nil || *delta.SwapIn != 2*mib {
+		t.Errorf("SwapIn = %v, want %d", delta.SwapIn, 2*mib)
+	}
+	if delta.SwapOut == nil || *delta.SwapOut != 0 {
+		t.Errorf("SwapOut = %v, want 0", delta.SwapOut)
+	}
+}
+
+func TestNewMemoryDeltaMissingSwapCounters(t *testing.T) {
+	withCounters := &MemoryInfo{swap: &swapCounters{In: 10 * mib, Out: 20 * mib}}
+	withoutCounters := &MemoryInfo{}
+
+	// Neither a failed read nor a platform without counters may produce a swap delta
+	for _, pair := range [][2]*MemoryInfo{
+		{withoutCounters, withCounters},
+		{withCounters, withoutCounters},
+		{withoutCounters, withoutCounters},
+	} {
+		delta := newMemoryDelta(pair[0], pair[1], 1)
+		if delta.SwapIn != nil || delta.SwapOut != nil {
+			t.Errorf("expected no swap delta, got in=%v out=%v", delta.SwapIn, delta.SwapOut)
+		}
+	}
+}
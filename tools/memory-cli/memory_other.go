@@ -0,0 +1,15 @@
+//go:build !windows
+
+package main
+
+import "fmt"
+
+// getWindowsMemoryInfo is only available in Windows builds
+func getWindowsMemoryInfo() (*MemoryInfo, error) {
+	return nil, fmt.Errorf("windows memory info is not available on this platform")
+}
+
+// getWindowsProcesses is only available in Windows builds
+func getWindowsProcesses() ([]ProcessMemory, error) {
+	return nil, fmt.Errorf("windows process list is not available on this platform")
+}
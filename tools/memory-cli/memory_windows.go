@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// GlobalMemoryStatusEx, K32GetPerformanceInfo and K32GetProcessMemoryInfo are not
+// wrapped by x/sys/windows, so they are loaded from the system directory directly
+var (
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetPerformanceInfo   = kernel32.NewProc("K32GetPerformanceInfo")
+	procGetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+)
+
+// memoryStatusEx mirrors the Win32 MEMORYSTATUSEX structure
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// performanceInformation mirrors the Win32 PERFORMANCE_INFORMATION structure
+type performanceInformation struct {
+	Cb                uint32
+	CommitTotal       uintptr
+	CommitLimit       uintptr
+	CommitPeak        uintptr
+	PhysicalTotal     uintptr
+	PhysicalAvailable uintptr
+	SystemCache       uintptr
+	KernelTotal       uintptr
+	KernelPaged       uintptr
+	KernelNonpaged    uintptr
+	PageSize          uintptr
+	HandleCount       uint32
+	ProcessCount      uint32
+	ThreadCount       uint32
+}
+
+// processMemoryCountersEx mirrors the Win32 PROCESS_MEMORY_COUNTERS_EX structure
+type processMemoryCountersEx struct {
+	Cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+	PrivateUsage               uintptr
+}
+
+// systemPageFileInformation mirrors the NT SYSTEM_PAGEFILE_INFORMATION structure (sizes in pages)
+type systemPageFileInformation struct {
+	NextEntryOffset uint32
+	TotalSize       uint32
+	TotalInUse      uint32
+	PeakUsage       uint32
+	PageFileName    windows.NTUnicodeString
+}
+
+// getWindowsMemoryInfo gets memory info on Windows using GlobalMemoryStatusEx, paging file
+// information from NtQuerySystemInformation and GetPerformanceInfo
+func getWindowsMemoryInfo() (*MemoryInfo, error) {
+	memInfo := &MemoryInfo{Platform: "windows"}
+
+	status := memoryStatusEx{}
+	status.Length = uint32(unsafe.Sizeof(status))
+	if ret, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status))); ret == 0 {
+		return nil, fmt.Errorf("GlobalMemoryStatusEx failed: %v", err)
+	}
+
+	memInfo.TotalMemory = status.TotalPhys
+	memInfo.AvailableMemory = status.AvailPhys
+	memInfo.FreeMemory = status.AvailPhys
+	memInfo.UsedMemory = status.TotalPhys - status.AvailPhys
+	memInfo.UsagePercentage = calculateUsagePercentage(memInfo.UsedMemory, memInfo.TotalMemory)
+
+	// Swap failures are not fatal; the physical memory snapshot is still useful
+	if total, used, err := getPageFileUsage(); err == nil {
+		memInfo.SwapTotal = total
+		memInfo.SwapUsed = used
+		memInfo.SwapFree = total - used
+	}
+
+	// System cache size is optional; keep the snapshot if the call is unavailable
+	perf := performanceInformation{}
+	perf.Cb = uint32(unsafe.Sizeof(perf))
+	if procGetPerformanceInfo.Find() == nil {
+		if ret, _, _ := procGetPerformanceInfo.Call(uintptr(unsafe.Pointer(&perf)), uintptr(perf.Cb)); ret != 0 {
+			memInfo.CachedMemory = uint64(perf.SystemCache) * uint64(perf.PageSize)
+		}
+	}
+
+	return memInfo, nil
+}
+
+// getPageFileUsage sums the size and usage of all paging files in bytes
+func getPageFileUsage() (uint64, uint64, error) {
+	buf := make([]byte, 1024)
+	for {
+		var retLen uint32
+		err := windows.NtQuerySystemInformation(windows.SystemPageFileInformation,
+			unsafe.Pointer(&buf[0]), uint32(len(buf)), &retLen)
+		if err == nil {
+			buf = buf[:retLen]
+			break
+		}
+		if err != windows.STATUS_INFO_LENGTH_MISMATCH {
+			return 0, 0, fmt.Errorf("NtQuerySystemInformation failed: %v", err)
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	var total, used uint64
+	for offset := uint32(0); int(offset)+int(unsafe.Sizeof(systemPageFileInformation{})) <= len(buf); {
+		info := (*systemPageFileInformation)(unsafe.Pointer(&buf[offset]))
+		total += uint64(info.TotalSize) * pageSize
+		used += uint64(info.TotalInUse) * pageSize
+		if info.NextEntryOffset == 0 {
+			break
+		}
+		offset += info.NextEntryOffset
+	}
+
+	return total, used, nil
+}
+
+// getWindowsProcesses lists processes with their working set and private bytes
+func getWindowsProcesses() ([]ProcessMemory, error) {
+	pids, err := enumProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	// Shared by all name lookups; a MAX_LONG_PATH buffer per process adds up in watch mode
+	nameBuf := make([]uint16, windows.MAX_LONG_PATH)
+
+	var procs []ProcessMemory
+	for _, pid := range pids {
+		if pid == 0 {
+			continue // System Idle Process
+		}
+
+		// Protected and exited processes cannot be opened; skip them silently
+		handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+		if err != nil {
+			continue
+		}
+
+		counters := processMemoryCountersEx{}
+		counters.Cb = uint32(unsafe.Sizeof(counters))
+		ret, _, _ := procGetProcessMemoryInfo.Call(uintptr(handle), uintptr(unsafe.Pointer(&counters)), uintptr(counters.Cb))
+		name := getProcessName(handle, nameBuf)
+		windows.CloseHandle(handle)
+		if ret == 0 {
+			continue
+		}
+
+		procs = append(procs, ProcessMemory{
+			PID:          int(pid),
+			Name:         name,
+			RSS:          uint64(counters.WorkingSetSize),
+			PrivateBytes: uint64(counters.PrivateUsage),
+		})
+	}
+
+	return procs, nil
+}
+
+// enumProcesses returns the IDs of all running processes, growing the buffer until it fits
+func enumProcesses() ([]uint32, error) {
+	pids := make([]uint32, 1024)
+	for {
+		var bytesReturned uint32
+		if err := windows.EnumProcesses(pids, &bytesReturned); err != nil {
+			return nil, fmt.Errorf("EnumProcesses failed: %v", err)
+		}
+		// bytesReturned counts bytes (4 per PID); a full buffer may mean the list was truncated
+		if count := int(bytesReturned) / 4; count < len(pids) {
+			return pids[:count], nil
+		}
+		pids = make([]uint32, 2*len(pids))
+	}
+}
+
+// getProcessName returns the executable name of an open process ("" if unavailable),
+// using buf as scratch space
+func getProcessName(handle windows.Handle, buf []uint16) string {
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buf[0], &size); err != nil {
+		return ""
+	}
+	return filepath.Base(windows.UTF16ToString(buf[:size]))
+}